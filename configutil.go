@@ -2,13 +2,7 @@ package configutil
 
 import (
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"runtime"
-	"strconv"
-
-	"github.com/joho/godotenv"
+	"reflect"
 )
 
 type Config struct {
@@ -17,8 +11,16 @@ type Config struct {
 	Debug              bool
 	Port               string
 	EnvFile            string
+	EnvFiles           []string
+	Mode               string
+	Sources            []Source
+	AppName            string
+	SecretResolver     SecretResolver
+	Watch              bool
 	GoogleClientID     string
 	GoogleClientSecret string
+
+	trace map[string]string
 }
 
 type Option func(*Config)
@@ -77,6 +79,92 @@ func WithGoogleClientSecret(clientSecret string) Option {
 	}
 }
 
+// WithMode selects the deployment mode (e.g. "development", "production")
+// used to build the cascading env file precedence chain in envFilePaths:
+// .env.{mode}.local, .env.local, .env.{mode}, .env, earlier files
+// winning over later ones. It mirrors the APP_MODE environment
+// variable, which is used when no mode is set explicitly. Opting into a
+// mode also flips precedence between the cascade and the OS
+// environment: the OS environment wins over the whole merged cascade,
+// unlike the single-file default (see WithEnvFile), where the dotenv
+// file wins.
+func WithMode(mode string) Option {
+	return func(c *Config) {
+		if mode != "" {
+			c.Mode = mode
+		}
+	}
+}
+
+// WithEnvFiles overrides the env file precedence chain with an explicit
+// ordered list of paths, earlier entries taking priority over later
+// ones. Missing files are skipped silently. Like WithMode, this is a
+// cascade: the OS environment wins over all of the listed files.
+func WithEnvFiles(paths ...string) Option {
+	return func(c *Config) {
+		if len(paths) > 0 {
+			c.EnvFiles = paths
+		}
+	}
+}
+
+// WithAppName sets the application name used to probe XDG Base
+// Directory locations (and %APPDATA% on Windows) for a default config
+// file when neither EnvFile nor EnvFiles is set. With a mode set, the
+// XDG paths follow the mode cascade. See envFilePaths.
+func WithAppName(name string) Option {
+	return func(c *Config) {
+		if name != "" {
+			c.AppName = name
+		}
+	}
+}
+
+// WithSecretResolver installs a SecretResolver used to expand indirect
+// secret references. Expansion, including the built-in "file://" and
+// "env://" forms, only runs against fields tagged secret:"true" —
+// unless a resolver is installed, in which case it runs against every
+// field, since a custom scheme like "vault://" or "gcpsm://" can't be
+// told apart from an ordinary value without one.
+func WithSecretResolver(resolver SecretResolver) Option {
+	return func(c *Config) {
+		if resolver != nil {
+			c.SecretResolver = resolver
+		}
+	}
+}
+
+// WithWatch opts into hot reloading when building a Watcher via
+// NewWatcher; NewConfig ignores it and always returns a single static
+// snapshot, so existing callers are unaffected.
+func WithWatch(watch bool) Option {
+	return func(c *Config) {
+		c.Watch = watch
+	}
+}
+
+// WithSources overrides the default env/dotenv lookup chain with an
+// explicit, user-composed list of sources, consulted in order. The
+// first source reporting a key wins.
+func WithSources(sources ...Source) Option {
+	return func(c *Config) {
+		if len(sources) > 0 {
+			c.Sources = sources
+		}
+	}
+}
+
+// configFields mirrors Config as a tagged struct so NewConfig can be
+// implemented as a thin wrapper over the generic Load.
+type configFields struct {
+	DatabaseURL        string `env:"DATABASE_URL" required:"true"`
+	AuthServiceURL     string `env:"AUTH_SERVICE_URL" required:"true"`
+	Debug              bool   `env:"DEBUG"`
+	Port               string `env:"PORT"`
+	GoogleClientID     string `env:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `env:"GOOGLE_CLIENT_SECRET" secret:"true"`
+}
+
 func NewConfig(opts ...Option) (*Config, error) {
 	c := &Config{}
 
@@ -84,17 +172,31 @@ func NewConfig(opts ...Option) (*Config, error) {
 		opt(c)
 	}
 
-	envs, err := loadEnv(c.EnvFile)
+	sources, err := buildSources(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load environment: %w", err)
 	}
 
-	c.DatabaseURL = getEnvWithFallback(envs, "DATABASE_URL", c.DatabaseURL)
-	c.AuthServiceURL = getEnvWithFallback(envs, "AUTH_SERVICE_URL", c.AuthServiceURL)
-	c.Debug = getBoolEnvWithFallback(envs, "DEBUG", c.Debug)
-	c.Port = getEnvWithFallback(envs, "PORT", c.Port)
-	c.GoogleClientID = getEnvWithFallback(envs, "GOOGLE_CLIENT_ID", c.GoogleClientID)
-	c.GoogleClientSecret = getEnvWithFallback(envs, "GOOGLE_CLIENT_SECRET", c.GoogleClientSecret)
+	fields := configFields{
+		DatabaseURL:        c.DatabaseURL,
+		AuthServiceURL:     c.AuthServiceURL,
+		Debug:              c.Debug,
+		Port:               c.Port,
+		GoogleClientID:     c.GoogleClientID,
+		GoogleClientSecret: c.GoogleClientSecret,
+	}
+	trace := make(map[string]string)
+	if err := bindStruct(reflect.ValueOf(&fields).Elem(), sources, "", trace, c.SecretResolver); err != nil {
+		return nil, err
+	}
+	c.trace = trace
+
+	c.DatabaseURL = fields.DatabaseURL
+	c.AuthServiceURL = fields.AuthServiceURL
+	c.Debug = fields.Debug
+	c.Port = fields.Port
+	c.GoogleClientID = fields.GoogleClientID
+	c.GoogleClientSecret = fields.GoogleClientSecret
 
 	if err := c.validate(); err != nil {
 		return nil, err
@@ -113,62 +215,28 @@ func (c *Config) validate() error {
 	return nil
 }
 
-func getEnvWithFallback(envs map[string]string, key, fallback string) string {
-	if value, exists := envs[key]; exists && value != "" {
-		return value
-	}
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		return value
-	}
-	return fallback
-}
-
-func getBoolEnvWithFallback(envs map[string]string, key string, fallback bool) bool {
-	strValue := getEnvWithFallback(envs, key, strconv.FormatBool(fallback))
-	boolValue, err := strconv.ParseBool(strValue)
-	if err != nil {
-		log.Printf("Warning: invalid boolean value for %s, using fallback", key)
-		return fallback
-	}
-	return boolValue
+// String implements fmt.Stringer, redacting fields tagged secret:"true"
+// (currently GoogleClientSecret) so accidental logging of a Config
+// doesn't leak credentials.
+func (c *Config) String() string {
+	return redactedString(configFields{
+		DatabaseURL:        c.DatabaseURL,
+		AuthServiceURL:     c.AuthServiceURL,
+		Debug:              c.Debug,
+		Port:               c.Port,
+		GoogleClientID:     c.GoogleClientID,
+		GoogleClientSecret: c.GoogleClientSecret,
+	})
 }
 
-func loadEnv(envFile string) (map[string]string, error) {
-	if envFile == "" {
-		envFile = os.Getenv("ENV_FILE")
-		if envFile == "" {
-			_, b, _, _ := runtime.Caller(0)
-			basepath := filepath.Dir(b)
-			envFile = findEnvFilePath(basepath)
-		}
-	}
-
-	envs, err := godotenv.Read(envFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("Warning: .env file not found at %s, using only OS environment variables", envFile)
-			return make(map[string]string), nil
-		}
-		return nil, fmt.Errorf("error reading .env file: %w", err)
-	}
-	return envs, nil
-}
-
-// findEnvFilePath recursively searches for the .env file in parent directories
-func findEnvFilePath(startDir string) string {
-	dir := startDir
-	for {
-		envFilePath := filepath.Join(dir, ".env")
-		if _, err := os.Stat(envFilePath); err == nil {
-			return envFilePath
-		}
-		// Move up one directory
-		parentDir := filepath.Dir(dir)
-		if parentDir == dir {
-			// Reached the root directory
-			break
-		}
-		dir = parentDir
+// Trace reports, for each resolved field's env key, the name of the
+// source that supplied its value ("env", "dotenv", "default", or a
+// custom source's name per sourceName). It's meant for debugging why a
+// value came from where it did, not for production decision-making.
+func (c *Config) Trace() map[string]string {
+	out := make(map[string]string, len(c.trace))
+	for k, v := range c.trace {
+		out[k] = v
 	}
-	return ""
+	return out
 }
@@ -0,0 +1,171 @@
+package configutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/joho/godotenv"
+)
+
+// envFilePaths resolves the ordered list of env files to read for c,
+// highest priority first.
+//
+//  1. c.EnvFiles, if set, used verbatim.
+//  2. c.EnvFile, a single explicit path.
+//  3. Otherwise, the mode-aware cascade and the XDG Base Directory
+//     search are merged, mode cascade first: when a mode is set via
+//     WithMode or the APP_MODE environment variable, .env.{mode}.local,
+//     .env.local, .env.{mode}, .env; then, when c.AppName is set via
+//     WithAppName, the search performed by xdgConfigPaths. A CLI that
+//     sets both gets the mode-specific, cwd-relative files first and
+//     the installed XDG config as a fallback beneath them.
+//  4. If no mode was set, the legacy lookup, appended last: $ENV_FILE
+//     if set, otherwise a bare .env file found by walking up from this
+//     package's source directory.
+//
+// The returned paths are merged by mergeEnvFiles, which skips missing
+// files silently.
+func envFilePaths(c *Config) []string {
+	if len(c.EnvFiles) > 0 {
+		return c.EnvFiles
+	}
+
+	if c.EnvFile != "" {
+		return []string{c.EnvFile}
+	}
+
+	var paths []string
+
+	mode := c.Mode
+	if mode == "" {
+		mode = os.Getenv("APP_MODE")
+	}
+	if mode != "" {
+		paths = append(paths,
+			fmt.Sprintf(".env.%s.local", mode),
+			".env.local",
+			fmt.Sprintf(".env.%s", mode),
+			".env",
+		)
+	}
+
+	if c.AppName != "" {
+		paths = append(paths, xdgConfigPaths(c.AppName)...)
+	}
+
+	if mode == "" {
+		envFile := os.Getenv("ENV_FILE")
+		if envFile == "" {
+			_, b, _, _ := runtime.Caller(0)
+			basepath := filepath.Dir(b)
+			envFile = findEnvFilePath(basepath)
+		}
+		if envFile != "" {
+			paths = append(paths, envFile)
+		}
+	}
+
+	return paths
+}
+
+// isCascade reports whether c resolves to a multi-file precedence
+// chain (the mode-aware cascade or an explicit WithEnvFiles list)
+// rather than a single dotenv file. buildSources uses this to decide
+// whether the OS environment should outrank the merged file values, per
+// the precedence documented on WithMode.
+func isCascade(c *Config) bool {
+	if len(c.EnvFiles) > 0 {
+		return true
+	}
+	mode := c.Mode
+	if mode == "" {
+		mode = os.Getenv("APP_MODE")
+	}
+	return mode != ""
+}
+
+// xdgConfigPaths returns the XDG Base Directory search path for
+// appName's config.env file, highest priority first:
+// $XDG_CONFIG_HOME/<appName>/config.env (falling back to
+// $HOME/.config/<appName>/config.env), then each directory in
+// $XDG_CONFIG_DIRS (default /etc/xdg), then /etc/<appName>/config.env.
+// On Windows, this is replaced by %APPDATA%\<appName>\config.env.
+func xdgConfigPaths(appName string) []string {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return []string{filepath.Join(appData, appName, "config.env")}
+		}
+		return nil
+	}
+
+	var paths []string
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, appName, "config.env"))
+	}
+
+	configDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+	for _, dir := range filepath.SplitList(configDirs) {
+		if dir != "" {
+			paths = append(paths, filepath.Join(dir, appName, "config.env"))
+		}
+	}
+
+	paths = append(paths, filepath.Join("/etc", appName, "config.env"))
+	return paths
+}
+
+// mergeEnvFiles reads each path in order and merges them into a single
+// map, with keys from earlier paths taking precedence over later ones.
+// Paths that don't exist are skipped silently.
+func mergeEnvFiles(paths []string) (map[string]string, error) {
+	merged := make(map[string]string)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		envs, err := godotenv.Read(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading %s: %w", path, err)
+		}
+		for k, v := range envs {
+			if _, exists := merged[k]; !exists {
+				merged[k] = v
+			}
+		}
+	}
+	return merged, nil
+}
+
+// findEnvFilePath recursively searches for the .env file in parent directories
+func findEnvFilePath(startDir string) string {
+	dir := startDir
+	for {
+		envFilePath := filepath.Join(dir, ".env")
+		if _, err := os.Stat(envFilePath); err == nil {
+			return envFilePath
+		}
+		// Move up one directory
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			// Reached the root directory
+			break
+		}
+		dir = parentDir
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package configutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewConfigSingleEnvFileDotenvWinsOverOS(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("DATABASE_URL=from-dotenv\nAUTH_SERVICE_URL=http://auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DATABASE_URL", "from-os-env")
+
+	cfg, err := NewConfig(WithEnvFile(envPath))
+	if err != nil {
+		t.Fatalf("NewConfig: %v", err)
+	}
+	if cfg.DatabaseURL != "from-dotenv" {
+		t.Fatalf("DatabaseURL = %q, want %q (a single dotenv file should win over a stale OS var)", cfg.DatabaseURL, "from-dotenv")
+	}
+}
+
+func TestNewConfigModeCascadeOSWinsOverFiles(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("DATABASE_URL=from-dotenv\nAUTH_SERVICE_URL=http://auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DATABASE_URL", "from-os-env")
+
+	withCwd(t, dir, func() {
+		cfg, err := NewConfig(WithMode("production"))
+		if err != nil {
+			t.Fatalf("NewConfig: %v", err)
+		}
+		if cfg.DatabaseURL != "from-os-env" {
+			t.Fatalf("DatabaseURL = %q, want %q (OS should win once a mode cascade is in play)", cfg.DatabaseURL, "from-os-env")
+		}
+	})
+}
+
+func TestMergeEnvFilesEarlierWinsAndMissingSkipped(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.env")
+	b := filepath.Join(dir, "b.env")
+	missing := filepath.Join(dir, "missing.env")
+
+	if err := os.WriteFile(a, []byte("KEY=from-a\nONLY_A=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("KEY=from-b\nONLY_B=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := mergeEnvFiles([]string{a, missing, b})
+	if err != nil {
+		t.Fatalf("mergeEnvFiles: %v", err)
+	}
+	if merged["KEY"] != "from-a" {
+		t.Fatalf("KEY = %q, want %q (earlier file should win)", merged["KEY"], "from-a")
+	}
+	if merged["ONLY_B"] != "1" {
+		t.Fatal("expected a value only present in the later file to still be merged in")
+	}
+}
+
+func TestEnvFilePathsModeCascadeOrder(t *testing.T) {
+	c := &Config{Mode: "production"}
+	got := envFilePaths(c)
+	want := []string{
+		".env.production.local",
+		".env.local",
+		".env.production",
+		".env",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("envFilePaths = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("envFilePaths[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestXDGConfigPathsUsesConfigHomeAndDirs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/u/.config")
+	t.Setenv("XDG_CONFIG_DIRS", "/etc/xdg1:/etc/xdg2")
+
+	got := xdgConfigPaths("myapp")
+	want := []string{
+		filepath.Join("/home/u/.config", "myapp", "config.env"),
+		filepath.Join("/etc/xdg1", "myapp", "config.env"),
+		filepath.Join("/etc/xdg2", "myapp", "config.env"),
+		filepath.Join("/etc", "myapp", "config.env"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("xdgConfigPaths = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("xdgConfigPaths[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEnvFilePathsMergesModeAndAppName(t *testing.T) {
+	c := &Config{Mode: "production", AppName: "myapp"}
+	got := envFilePaths(c)
+
+	want := append([]string{
+		".env.production.local",
+		".env.local",
+		".env.production",
+		".env",
+	}, xdgConfigPaths("myapp")...)
+
+	if len(got) != len(want) {
+		t.Fatalf("envFilePaths = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("envFilePaths[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
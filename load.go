@@ -0,0 +1,185 @@
+package configutil
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// Load populates the exported fields of the struct pointed to by target
+// from the configured Source chain (see WithSources), using struct tags
+// to describe each field:
+//
+//	env:"NAME"        the environment variable to read
+//	default:"value"   used when the variable is unset
+//	required:"true"   Load fails if the variable is unset and the field
+//	                  still has its zero value after defaults are applied
+//	secret:"true"     marks the field as sensitive (see SecretResolver)
+//	envPrefix:"PFX_"  on a nested struct field, prefixes all of its
+//	                  descendants' env tags
+//
+// Supported field types are string, bool, the int family, time.Duration,
+// []string (comma-separated), url.URL, and nested structs. A value that
+// fails to parse for its field's type is a hard error for a required
+// field; otherwise it's logged as a warning and the field keeps whatever
+// value it already had, matching the warn-and-fallback behavior the
+// package has always had for booleans.
+func Load(target interface{}, opts ...Option) error {
+	c := &Config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	sources, err := buildSources(c)
+	if err != nil {
+		return fmt.Errorf("failed to load environment: %w", err)
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("configutil: Load target must be a pointer to a struct")
+	}
+
+	return bindStruct(v.Elem(), sources, "", nil, c.SecretResolver)
+}
+
+func bindStruct(v reflect.Value, sources []namedSource, prefix string, trace map[string]string, resolver SecretResolver) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != urlType {
+			nestedPrefix := prefix + field.Tag.Get("envPrefix")
+			if err := bindStruct(fv, sources, nestedPrefix, trace, resolver); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		key = prefix + key
+
+		raw, src, ok := resolve(sources, key)
+		if !ok {
+			if def := field.Tag.Get("default"); def != "" {
+				raw, src, ok = def, "default", true
+			} else if fv.IsZero() && field.Tag.Get("required") == "true" {
+				return fmt.Errorf("configutil: required environment variable %s is not set", key)
+			} else {
+				continue
+			}
+		}
+
+		// Reference expansion (the built-in file:// and env:// forms,
+		// plus the custom resolver's schemes) runs for secret-tagged
+		// fields, or for every field once a resolver is installed.
+		// Other fields keep their literal value.
+		if field.Tag.Get("secret") == "true" || resolver != nil {
+			resolved, err := resolveSecretValue(raw, resolver)
+			if err != nil {
+				return fmt.Errorf("configutil: field %s (%s): %w", field.Name, key, err)
+			}
+			raw = resolved
+		}
+
+		if err := setField(fv, raw); err != nil {
+			if field.Tag.Get("required") == "true" {
+				return fmt.Errorf("configutil: field %s (%s): %w", field.Name, key, err)
+			}
+			log.Printf("Warning: invalid value for %s, using fallback: %v", key, err)
+			continue
+		}
+		if trace != nil {
+			trace[key] = src
+		}
+	}
+	return nil
+}
+
+func setField(fv reflect.Value, raw string) error {
+	switch fv.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(d))
+		return nil
+	case url.URL:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice element type %s", fv.Type().Elem())
+		}
+		parts := strings.Split(raw, ",")
+		for i, p := range parts {
+			parts[i] = strings.TrimSpace(p)
+		}
+		fv.Set(reflect.ValueOf(parts))
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// redactedString formats a struct as its field names and values,
+// replacing any field tagged secret:"true" with "REDACTED". It backs Stringer implementations on types that embed
+// secret values, so that logging one doesn't leak credentials.
+func redactedString(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	t := rv.Type()
+
+	var b strings.Builder
+	b.WriteString(t.Name())
+	b.WriteByte('{')
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(field.Name)
+		b.WriteByte(':')
+		if field.Tag.Get("secret") == "true" {
+			b.WriteString("REDACTED")
+			continue
+		}
+		fmt.Fprintf(&b, "%v", rv.Field(i).Interface())
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package configutil
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLoadInvalidBoolFallsBack(t *testing.T) {
+	type target struct {
+		Debug bool `env:"TEST_CHUNK0_1_DEBUG"`
+	}
+
+	t.Setenv("TEST_CHUNK0_1_DEBUG", "notabool")
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load returned an error for a non-required invalid bool: %v", err)
+	}
+	if c.Debug {
+		t.Fatalf("expected Debug to keep its zero-value fallback, got %v", c.Debug)
+	}
+}
+
+func TestLoadInvalidBoolRequiredFails(t *testing.T) {
+	type target struct {
+		Debug bool `env:"TEST_CHUNK0_1_DEBUG_REQUIRED" required:"true"`
+	}
+
+	t.Setenv("TEST_CHUNK0_1_DEBUG_REQUIRED", "notabool")
+
+	var c target
+	if err := Load(&c); err == nil {
+		t.Fatal("expected Load to fail for a required field with an invalid value")
+	}
+}
+
+func TestLoadNestedStructEnvPrefix(t *testing.T) {
+	type db struct {
+		Host string `env:"HOST" required:"true"`
+		Port string `env:"PORT"`
+	}
+	type target struct {
+		DB db `envPrefix:"TEST_CHUNK0_1_DB_"`
+	}
+
+	t.Setenv("TEST_CHUNK0_1_DB_HOST", "db.internal")
+	t.Setenv("TEST_CHUNK0_1_DB_PORT", "5432")
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.DB.Host != "db.internal" {
+		t.Fatalf("DB.Host = %q, want %q", c.DB.Host, "db.internal")
+	}
+	if c.DB.Port != "5432" {
+		t.Fatalf("DB.Port = %q, want %q", c.DB.Port, "5432")
+	}
+}
+
+func TestLoadDurationSliceAndURL(t *testing.T) {
+	type target struct {
+		Timeout time.Duration `env:"TEST_CHUNK0_1_TIMEOUT"`
+		Hosts   []string      `env:"TEST_CHUNK0_1_HOSTS"`
+		Addr    url.URL       `env:"TEST_CHUNK0_1_ADDR"`
+	}
+
+	t.Setenv("TEST_CHUNK0_1_TIMEOUT", "5s")
+	t.Setenv("TEST_CHUNK0_1_HOSTS", "a.example.com, b.example.com")
+	t.Setenv("TEST_CHUNK0_1_ADDR", "https://example.com:8443/path")
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Timeout != 5*time.Second {
+		t.Fatalf("Timeout = %v, want %v", c.Timeout, 5*time.Second)
+	}
+	if want := []string{"a.example.com", "b.example.com"}; len(c.Hosts) != len(want) || c.Hosts[0] != want[0] || c.Hosts[1] != want[1] {
+		t.Fatalf("Hosts = %v, want %v", c.Hosts, want)
+	}
+	if c.Addr.Host != "example.com:8443" || c.Addr.Path != "/path" {
+		t.Fatalf("Addr = %+v, want host %q path %q", c.Addr, "example.com:8443", "/path")
+	}
+}
@@ -0,0 +1,50 @@
+package configutil
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretResolver resolves an indirect secret reference — typically a
+// scheme-prefixed string such as "vault://path#field" or
+// "gcpsm://projects/x/secrets/y" — to its literal value. Implementations
+// plug in backends like Vault, AWS Secrets Manager, or GCP Secret
+// Manager; see WithSecretResolver.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// resolveSecretValue expands the built-in "file://" and "env://"
+// reference forms unconditionally, and falls back to resolver for any
+// other scheme. A value with no recognized scheme, or one with an
+// unrecognized scheme and no resolver, is returned unchanged.
+func resolveSecretValue(raw string, resolver SecretResolver) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "file://"):
+		path := strings.TrimPrefix(raw, "file://")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading secret file %s: %w", path, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+
+	case strings.HasPrefix(raw, "env://"):
+		key := strings.TrimPrefix(raw, "env://")
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", fmt.Errorf("env indirection %s: %s is not set", raw, key)
+		}
+		return value, nil
+
+	default:
+		if resolver == nil {
+			return raw, nil
+		}
+		resolved, err := resolver.Resolve(raw)
+		if err != nil {
+			return "", err
+		}
+		return resolved, nil
+	}
+}
@@ -0,0 +1,110 @@
+package configutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSecretFileReference(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("s3cr3t\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type target struct {
+		Token string `env:"TEST_CHUNK0_5_TOKEN" secret:"true"`
+	}
+
+	t.Setenv("TEST_CHUNK0_5_TOKEN", "file://"+path)
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Token != "s3cr3t" {
+		t.Fatalf("Token = %q, want %q", c.Token, "s3cr3t")
+	}
+}
+
+func TestLoadSecretEnvReference(t *testing.T) {
+	type target struct {
+		Token string `env:"TEST_CHUNK0_5_TOKEN_ENV" secret:"true"`
+	}
+
+	t.Setenv("TEST_CHUNK0_5_REAL_TOKEN", "real-value")
+	t.Setenv("TEST_CHUNK0_5_TOKEN_ENV", "env://TEST_CHUNK0_5_REAL_TOKEN")
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Token != "real-value" {
+		t.Fatalf("Token = %q, want %q", c.Token, "real-value")
+	}
+}
+
+// TestLoadFileReferenceNotExpandedWithoutSecretTagOrResolver documents the
+// gating rule WithSecretResolver describes: file:// and env:// are only
+// expanded against secret:"true" fields, or every field once a resolver
+// is installed. A plain field with neither gets the literal string.
+func TestLoadFileReferenceNotExpandedWithoutSecretTagOrResolver(t *testing.T) {
+	type target struct {
+		Path string `env:"TEST_CHUNK0_5_PLAIN_PATH"`
+	}
+
+	t.Setenv("TEST_CHUNK0_5_PLAIN_PATH", "file:///tmp/does-not-matter")
+
+	var c target
+	if err := Load(&c); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Path != "file:///tmp/does-not-matter" {
+		t.Fatalf("Path = %q, want the literal value unexpanded", c.Path)
+	}
+}
+
+type stubResolver struct {
+	resolved string
+}
+
+func (r stubResolver) Resolve(ref string) (string, error) {
+	return r.resolved, nil
+}
+
+func TestLoadResolverAppliesToEveryFieldOnceInstalled(t *testing.T) {
+	type target struct {
+		Name string `env:"TEST_CHUNK0_5_VAULT_NAME"`
+	}
+
+	t.Setenv("TEST_CHUNK0_5_VAULT_NAME", "vault://secret/data/name")
+
+	var c target
+	if err := Load(&c, WithSecretResolver(stubResolver{resolved: "resolved-name"})); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Name != "resolved-name" {
+		t.Fatalf("Name = %q, want %q", c.Name, "resolved-name")
+	}
+}
+
+func TestConfigStringRedactsSecretFields(t *testing.T) {
+	c, err := NewConfig(
+		WithDatabaseURL("postgres://x"),
+		WithAuthServiceURL("http://auth"),
+		WithGoogleClientSecret("super-secret"),
+	)
+	if err != nil {
+		t.Fatalf("NewConfig: %v", err)
+	}
+
+	s := c.String()
+	if want := "GoogleClientSecret:REDACTED"; !strings.Contains(s, want) {
+		t.Fatalf("String() = %q, want it to contain %q", s, want)
+	}
+	if strings.Contains(s, "super-secret") {
+		t.Fatalf("String() = %q, leaked the secret value", s)
+	}
+}
@@ -0,0 +1,219 @@
+package configutil
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source resolves a single configuration key to a string value. It is
+// the building block of the lookup chain NewConfig and Load use to
+// resolve env tags: sources are consulted in order and the first one
+// reporting ok=true wins.
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// namedSource pairs a Source with a human-readable label used by
+// Config.Trace() to report which source resolved a given key.
+type namedSource struct {
+	name string
+	src  Source
+}
+
+// EnvSource resolves keys against the OS environment.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// DotenvSource resolves keys against one or more merged dotenv files,
+// earlier paths taking precedence over later ones. Missing files are
+// skipped silently.
+type DotenvSource struct {
+	paths  []string
+	values map[string]string
+}
+
+// NewDotenvSource reads and merges paths in order.
+func NewDotenvSource(paths ...string) (*DotenvSource, error) {
+	values, err := mergeEnvFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	return &DotenvSource{paths: paths, values: values}, nil
+}
+
+func (s *DotenvSource) Lookup(key string) (string, bool) {
+	v, ok := s.values[key]
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// WatchPaths reports the files this source was built from, so a
+// Watcher knows what to watch for changes.
+func (s *DotenvSource) WatchPaths() []string {
+	return s.paths
+}
+
+// FileSource resolves keys against a flat JSON or YAML configuration
+// file, such as the one pointed to by REGISTRY_CONFIGURATION_PATH in
+// registry-style deployments. The format is chosen from the file
+// extension (.yaml/.yml vs everything else, treated as JSON).
+type FileSource struct {
+	path   string
+	values map[string]string
+}
+
+// NewFileSource reads and parses path. A missing file is treated as an
+// empty source rather than an error, matching DotenvSource.
+func NewFileSource(path string) (*FileSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &FileSource{path: path, values: map[string]string{}}, nil
+		}
+		return nil, fmt.Errorf("configutil: reading %s: %w", path, err)
+	}
+
+	raw := make(map[string]interface{})
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("configutil: parsing %s: %w", path, err)
+		}
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("configutil: parsing %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		values[k] = fmt.Sprintf("%v", v)
+	}
+	return &FileSource{path: path, values: values}, nil
+}
+
+func (s *FileSource) Lookup(key string) (string, bool) {
+	v, ok := s.values[key]
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// WatchPaths reports the file this source was built from, so a Watcher
+// knows what to watch for changes.
+func (s *FileSource) WatchPaths() []string {
+	return []string{s.path}
+}
+
+// MapSource is an in-memory Source, primarily useful in tests.
+type MapSource map[string]string
+
+func (s MapSource) Lookup(key string) (string, bool) {
+	v, ok := s[key]
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+// FlagSource resolves keys against a flag.FlagSet, matching a flag
+// whose name is either the key verbatim or the key lowercased with
+// underscores replaced by hyphens (so DATABASE_URL matches -database-url).
+type FlagSource struct {
+	fs *flag.FlagSet
+}
+
+func NewFlagSource(fs *flag.FlagSet) *FlagSource {
+	return &FlagSource{fs: fs}
+}
+
+func (s *FlagSource) Lookup(key string) (string, bool) {
+	if s.fs == nil {
+		return "", false
+	}
+	for _, name := range []string{key, strings.ToLower(strings.ReplaceAll(key, "_", "-"))} {
+		if f := s.fs.Lookup(name); f != nil {
+			return f.Value.String(), true
+		}
+	}
+	return "", false
+}
+
+// sourceName returns a human-readable label for a Source, used when the
+// caller supplies sources via WithSources rather than letting
+// buildSources name its own defaults.
+func sourceName(s Source) string {
+	switch s.(type) {
+	case EnvSource:
+		return "env"
+	case *DotenvSource:
+		return "dotenv"
+	case *FileSource:
+		return "file"
+	case MapSource:
+		return "map"
+	case *FlagSource:
+		return "flag"
+	default:
+		return fmt.Sprintf("%T", s)
+	}
+}
+
+// buildSources returns the lookup chain for c: c.Sources verbatim if the
+// caller set one via WithSources, otherwise the default chain built from
+// envFilePaths.
+//
+// Precedence between the OS environment and the dotenv file(s) depends
+// on whether c resolves to a cascade (see isCascade): for a plain
+// single file — WithEnvFile, or no options at all, which is how every
+// caller used this package before WithMode/WithEnvFiles existed — the
+// dotenv file wins, so a checked-in .env can override a stale shell
+// variable, exactly as the original getEnvWithFallback did. Opting into
+// the mode-aware cascade or an explicit WithEnvFiles list flips that:
+// the OS environment wins over the whole merged cascade, matching the
+// precedence WithMode documents.
+func buildSources(c *Config) ([]namedSource, error) {
+	if len(c.Sources) > 0 {
+		named := make([]namedSource, len(c.Sources))
+		for i, s := range c.Sources {
+			named[i] = namedSource{name: sourceName(s), src: s}
+		}
+		return named, nil
+	}
+
+	dotenv, err := NewDotenvSource(envFilePaths(c)...)
+	if err != nil {
+		return nil, err
+	}
+
+	env := namedSource{name: "env", src: EnvSource{}}
+	file := namedSource{name: "dotenv", src: dotenv}
+
+	if isCascade(c) {
+		return []namedSource{env, file}, nil
+	}
+	return []namedSource{file, env}, nil
+}
+
+// resolve consults sources in order and returns the value and name of
+// the first one that has key set.
+func resolve(sources []namedSource, key string) (value, source string, ok bool) {
+	for _, ns := range sources {
+		if v, found := ns.src.Lookup(key); found {
+			return v, ns.name, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,72 @@
+package configutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveFirstSourceWins(t *testing.T) {
+	sources := []namedSource{
+		{name: "first", src: MapSource{"KEY": "from-first"}},
+		{name: "second", src: MapSource{"KEY": "from-second", "OTHER": "from-second"}},
+	}
+
+	v, name, ok := resolve(sources, "KEY")
+	if !ok || v != "from-first" || name != "first" {
+		t.Fatalf("resolve(KEY) = (%q, %q, %v), want (%q, %q, true)", v, name, ok, "from-first", "first")
+	}
+
+	v, name, ok = resolve(sources, "OTHER")
+	if !ok || v != "from-second" || name != "second" {
+		t.Fatalf("resolve(OTHER) = (%q, %q, %v), want (%q, %q, true)", v, name, ok, "from-second", "second")
+	}
+
+	if _, _, ok := resolve(sources, "MISSING"); ok {
+		t.Fatal("resolve(MISSING) should report not found")
+	}
+}
+
+func TestWithSourcesOverridesDefaultChainAndTracesNames(t *testing.T) {
+	type target struct {
+		DatabaseURL string `env:"DATABASE_URL" required:"true"`
+	}
+
+	c := &Config{Sources: []Source{MapSource{"DATABASE_URL": "from-map"}}}
+	trace := make(map[string]string)
+
+	var v target
+	sources, err := buildSources(c)
+	if err != nil {
+		t.Fatalf("buildSources: %v", err)
+	}
+	if err := bindStruct(reflect.ValueOf(&v).Elem(), sources, "", trace, nil); err != nil {
+		t.Fatalf("bindStruct: %v", err)
+	}
+
+	if v.DatabaseURL != "from-map" {
+		t.Fatalf("DatabaseURL = %q, want %q", v.DatabaseURL, "from-map")
+	}
+	if trace["DATABASE_URL"] != "map" {
+		t.Fatalf("trace[DATABASE_URL] = %q, want %q", trace["DATABASE_URL"], "map")
+	}
+}
+
+func TestConfigTraceReportsSourceNames(t *testing.T) {
+	c, err := NewConfig(
+		WithSources(MapSource{
+			"DATABASE_URL":     "postgres://x",
+			"AUTH_SERVICE_URL": "http://auth",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewConfig: %v", err)
+	}
+
+	trace := c.Trace()
+	if trace["DATABASE_URL"] != "map" {
+		t.Fatalf("trace[DATABASE_URL] = %q, want %q", trace["DATABASE_URL"], "map")
+	}
+	if trace["AUTH_SERVICE_URL"] != "map" {
+		t.Fatalf("trace[AUTH_SERVICE_URL] = %q, want %q", trace["AUTH_SERVICE_URL"], "map")
+	}
+}
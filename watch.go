@@ -0,0 +1,186 @@
+package configutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher holds a hot-reloadable Config snapshot. Reads go through
+// Config, which is safe to call concurrently with a reload: the
+// snapshot is swapped atomically, never mutated in place.
+type Watcher struct {
+	current atomic.Pointer[Config]
+
+	opts      []Option
+	fsWatcher *fsnotify.Watcher
+	watched   map[string]struct{}
+	errors    chan error
+	stop      chan struct{}
+
+	mu          sync.Mutex
+	subscribers []func(old, new *Config)
+}
+
+// NewWatcher builds a Config the same way NewConfig does. If opts
+// includes WithWatch(true), it additionally watches the resolved
+// env file(s) — the default dotenv chain, or any source in
+// WithSources that implements WatchPaths() []string — via fsnotify,
+// reloading and atomically swapping the snapshot on each change.
+//
+// Most candidates in the mode-aware cascade (.env.{mode}.local,
+// .env.local, .env.{mode}, .env) and the XDG search don't exist in the
+// common case — mergeEnvFiles and envFilePaths already treat that as
+// normal. Watching must tolerate it too, so NewWatcher watches each
+// candidate's containing directory rather than the candidate file
+// itself: fsnotify can watch a directory that exists even when the file
+// inside it doesn't yet, and a cascade file created later is then
+// picked up without a restart. A directory that doesn't exist at
+// startup is skipped; files that later appear inside it still require a
+// restart to be noticed.
+//
+// A reload that fails validation leaves the previous snapshot active;
+// the error is delivered on the channel returned by Errors instead.
+func NewWatcher(opts ...Option) (*Watcher, error) {
+	cfg, err := NewConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		opts:   opts,
+		errors: make(chan error, 1),
+		stop:   make(chan struct{}),
+	}
+	w.current.Store(cfg)
+
+	if !cfg.Watch {
+		return w, nil
+	}
+
+	w.watched = make(map[string]struct{})
+	dirs := make(map[string]struct{})
+	for _, path := range watchPaths(cfg) {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		w.watched[path] = struct{}{}
+		dirs[filepath.Dir(path)] = struct{}{}
+	}
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("configutil: starting watcher: %w", err)
+	}
+	for dir := range dirs {
+		if err := fw.Add(dir); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			fw.Close()
+			return nil, fmt.Errorf("configutil: watching %s: %w", dir, err)
+		}
+	}
+	w.fsWatcher = fw
+
+	go w.run()
+	return w, nil
+}
+
+// watchPaths returns the files NewWatcher should watch for c: the
+// default dotenv chain, or, when c.Sources is set, the paths reported
+// by any source implementing WatchPaths() []string.
+func watchPaths(c *Config) []string {
+	if len(c.Sources) == 0 {
+		return envFilePaths(c)
+	}
+
+	var paths []string
+	for _, s := range c.Sources {
+		if wp, ok := s.(interface{ WatchPaths() []string }); ok {
+			paths = append(paths, wp.WatchPaths()...)
+		}
+	}
+	return paths
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if _, relevant := w.watched[filepath.Clean(event.Name)]; !relevant {
+				continue
+			}
+			w.reload()
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			w.reportError(err)
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	next, err := NewConfig(w.opts...)
+	if err != nil {
+		w.reportError(fmt.Errorf("configutil: reload failed, keeping previous config: %w", err))
+		return
+	}
+
+	old := w.current.Swap(next)
+
+	w.mu.Lock()
+	subs := append([]func(old, new *Config){}, w.subscribers...)
+	w.mu.Unlock()
+
+	for _, sub := range subs {
+		sub(old, next)
+	}
+}
+
+func (w *Watcher) reportError(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
+
+// Config returns the current snapshot.
+func (w *Watcher) Config() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called after every successful reload,
+// with the snapshot before and after the change.
+func (w *Watcher) Subscribe(fn func(old, new *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Errors returns the channel on which reload failures are delivered.
+// It's buffered by one; a failure that arrives while the buffer is
+// full is dropped rather than blocking the watch loop.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching and releases the underlying fsnotify watcher.
+func (w *Watcher) Close() error {
+	close(w.stop)
+	if w.fsWatcher != nil {
+		return w.fsWatcher.Close()
+	}
+	return nil
+}
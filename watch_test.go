@@ -0,0 +1,85 @@
+package configutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withCwd runs fn with the process working directory set to dir,
+// restoring the original on return.
+func withCwd(t *testing.T, dir string, fn func()) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(cwd); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	fn()
+}
+
+func TestNewWatcherCascadeWithOnlyBaseEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("DATABASE_URL=postgres://x\nAUTH_SERVICE_URL=http://auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	withCwd(t, dir, func() {
+		// .env.production.local, .env.local, and .env.production don't
+		// exist; only .env does. This must not fail.
+		w, err := NewWatcher(WithMode("production"), WithWatch(true))
+		if err != nil {
+			t.Fatalf("NewWatcher failed with only .env present: %v", err)
+		}
+		defer w.Close()
+
+		if got := w.Config().DatabaseURL; got != "postgres://x" {
+			t.Fatalf("DatabaseURL = %q, want %q", got, "postgres://x")
+		}
+	})
+}
+
+func TestNewWatcherReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	write := func(port string) {
+		content := "DATABASE_URL=postgres://x\nAUTH_SERVICE_URL=http://auth\nPORT=" + port + "\n"
+		if err := os.WriteFile(envPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("8080")
+
+	withCwd(t, dir, func() {
+		w, err := NewWatcher(WithEnvFile(envPath), WithWatch(true))
+		if err != nil {
+			t.Fatalf("NewWatcher: %v", err)
+		}
+		defer w.Close()
+
+		reloaded := make(chan *Config, 1)
+		w.Subscribe(func(old, next *Config) {
+			reloaded <- next
+		})
+
+		write("9090")
+
+		select {
+		case next := <-reloaded:
+			if next.Port != "9090" {
+				t.Fatalf("Port = %q, want %q", next.Port, "9090")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload after .env change")
+		}
+	})
+}